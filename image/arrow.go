@@ -0,0 +1,201 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/notnil/chess"
+	"golang.org/x/image/vector"
+)
+
+// arrow is a single arrow annotation from one square to another.
+type arrow struct {
+	from, to chess.Square
+	col      color.Color
+}
+
+// DrawArrow annotates the board with an arrow from from to to, colored
+// col, the way Lichess/Chess.com study diagrams mark tactical motifs.
+// Arrows between squares a knight's move apart are rendered with an L-bend
+// rather than cutting diagonally across the board.
+func DrawArrow(from, to chess.Square, col color.Color) Option {
+	return func(_ *config, e *encoder) {
+		e.arrows = append(e.arrows, arrow{from: from, to: to, col: col})
+	}
+}
+
+// CircleSquare annotates the board with a circle outline around sq,
+// colored col.
+func CircleSquare(sq chess.Square, col color.Color) Option {
+	return func(_ *config, e *encoder) {
+		e.circles[sq] = col
+	}
+}
+
+// isKnightMove reports whether two squares, given as absolute file and
+// rank deltas, are a knight's move apart.
+func isKnightMove(dFile, dRank int) bool {
+	return dFile+dRank == 3 && (dFile == 1 || dRank == 1)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// knightBend returns the intermediate point an arrow between a knight's
+// move should bend through: along the two-square leg first, then the
+// one-square leg, matching conventional knight-move rendering.
+func knightBend(fromX, fromY, toX, toY int) (bx, by int) {
+	if absInt(fromX-toX) == 2 {
+		return toX, fromY
+	}
+	return fromX, toY
+}
+
+const (
+	arrowLineWidth = 3.0
+	arrowHeadLen   = 10.0
+	arrowHeadWidth = 6.0
+	circleRadiusOf = 2.0 / 5.0
+)
+
+func (s *svgFormat) squareCenter(xIdx, yIdx int) (int, int) {
+	return xIdx*s.sqWidth + s.marginLeft + s.sqWidth/2, yIdx*s.sqHeight + s.sqHeight/2
+}
+
+func (s *svgFormat) DrawArrow(fromX, fromY, toX, toY int, col color.Color) error {
+	fx, fy := s.squareCenter(fromX, fromY)
+	tx, ty := s.squareCenter(toX, toY)
+	style := fmt.Sprintf("stroke: %s;stroke-width:%g", colorToHex(col), arrowLineWidth)
+
+	lastX, lastY := fx, fy
+	if isKnightMove(absInt(fromX-toX), absInt(fromY-toY)) {
+		bx, by := knightBend(fromX, fromY, toX, toY)
+		bpx, bpy := s.squareCenter(bx, by)
+		s.canvas.Line(fx, fy, bpx, bpy, style)
+		lastX, lastY = bpx, bpy
+	}
+	s.canvas.Line(lastX, lastY, tx, ty, style)
+	s.drawArrowheadSVG(tx, ty, lastX, lastY, col)
+	return nil
+}
+
+func (s *svgFormat) drawArrowheadSVG(tipX, tipY, fromX, fromY int, col color.Color) {
+	ux, uy, ok := unitVector(tipX, tipY, fromX, fromY)
+	if !ok {
+		return
+	}
+	px, py := -uy, ux
+
+	baseX := float64(tipX) - ux*arrowHeadLen
+	baseY := float64(tipY) - uy*arrowHeadLen
+
+	xs := []int{tipX, int(baseX + px*arrowHeadWidth), int(baseX - px*arrowHeadWidth)}
+	ys := []int{tipY, int(baseY + py*arrowHeadWidth), int(baseY - py*arrowHeadWidth)}
+	s.canvas.Polygon(xs, ys, "fill: "+colorToHex(col))
+}
+
+func (s *svgFormat) DrawCircle(xIdx, yIdx int, col color.Color) error {
+	cx, cy := s.squareCenter(xIdx, yIdx)
+	r := int(float64(s.sqWidth) * circleRadiusOf)
+	s.canvas.Circle(cx, cy, r, "fill: none;stroke: "+colorToHex(col)+";stroke-width:3")
+	return nil
+}
+
+// unitVector returns the unit vector pointing from (fromX, fromY) to
+// (toX, toY), and false if the two points coincide.
+func unitVector(toX, toY, fromX, fromY int) (ux, uy float64, ok bool) {
+	dx, dy := float64(toX-fromX), float64(toY-fromY)
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0, false
+	}
+	return dx / length, dy / length, true
+}
+
+func (c *rasterCanvas) squareCenter(xIdx, yIdx int) (int, int) {
+	return xIdx*c.sqWidth + c.marginLeft + c.sqWidth/2, yIdx*c.sqHeight + c.sqHeight/2
+}
+
+func (c *rasterCanvas) DrawArrow(fromX, fromY, toX, toY int, col color.Color) error {
+	fx, fy := c.squareCenter(fromX, fromY)
+	tx, ty := c.squareCenter(toX, toY)
+
+	lastX, lastY := fx, fy
+	if isKnightMove(absInt(fromX-toX), absInt(fromY-toY)) {
+		bx, by := knightBend(fromX, fromY, toX, toY)
+		bpx, bpy := c.squareCenter(bx, by)
+		c.drawLine(fx, fy, bpx, bpy, col)
+		lastX, lastY = bpx, bpy
+	}
+	c.drawLine(lastX, lastY, tx, ty, col)
+	c.drawArrowhead(tx, ty, lastX, lastY, col)
+	return nil
+}
+
+func (c *rasterCanvas) DrawCircle(xIdx, yIdx int, col color.Color) error {
+	cx, cy := c.squareCenter(xIdx, yIdx)
+	radius := float64(c.sqWidth) * circleRadiusOf
+
+	const segments = 32
+	px, py := cx+int(radius), cy
+	for i := 1; i <= segments; i++ {
+		theta := 2 * math.Pi * float64(i) / segments
+		x := cx + int(radius*math.Cos(theta))
+		y := cy + int(radius*math.Sin(theta))
+		c.drawLine(px, py, x, y, col)
+		px, py = x, y
+	}
+	return nil
+}
+
+// drawLine anti-aliases a line segment of width arrowLineWidth using
+// golang.org/x/image/vector.
+func (c *rasterCanvas) drawLine(x1, y1, x2, y2 int, col color.Color) {
+	ux, uy, ok := unitVector(x2, y2, x1, y1)
+	if !ok {
+		return
+	}
+	px, py := -uy*arrowLineWidth/2, ux*arrowLineWidth/2
+
+	c.fillPolygon(col,
+		float64(x1)+px, float64(y1)+py,
+		float64(x1)-px, float64(y1)-py,
+		float64(x2)-px, float64(y2)-py,
+		float64(x2)+px, float64(y2)+py,
+	)
+}
+
+func (c *rasterCanvas) drawArrowhead(tipX, tipY, fromX, fromY int, col color.Color) {
+	ux, uy, ok := unitVector(tipX, tipY, fromX, fromY)
+	if !ok {
+		return
+	}
+	px, py := -uy, ux
+
+	baseX := float64(tipX) - ux*arrowHeadLen
+	baseY := float64(tipY) - uy*arrowHeadLen
+
+	c.fillPolygon(col,
+		float64(tipX), float64(tipY),
+		baseX+px*arrowHeadWidth, baseY+py*arrowHeadWidth,
+		baseX-px*arrowHeadWidth, baseY-py*arrowHeadWidth,
+	)
+}
+
+// fillPolygon rasterizes and fills the polygon described by the flattened
+// (x, y) pairs in pts onto c.img.
+func (c *rasterCanvas) fillPolygon(col color.Color, pts ...float64) {
+	r := vector.NewRasterizer(c.width, c.height)
+	r.MoveTo(float32(pts[0]), float32(pts[1]))
+	for i := 2; i < len(pts); i += 2 {
+		r.LineTo(float32(pts[i]), float32(pts[i+1]))
+	}
+	r.ClosePath()
+	r.Draw(c.img, c.img.Bounds(), image.NewUniform(col), image.Point{})
+}
@@ -0,0 +1,36 @@
+package image
+
+import "testing"
+
+func TestIsKnightMove(t *testing.T) {
+	tests := []struct {
+		dFile, dRank int
+		want         bool
+	}{
+		{1, 2, true},
+		{2, 1, true},
+		{0, 3, false},
+		{3, 0, false},
+		{2, 2, false},
+		{1, 1, false},
+		{0, 0, false},
+	}
+	for _, tt := range tests {
+		if got := isKnightMove(tt.dFile, tt.dRank); got != tt.want {
+			t.Errorf("isKnightMove(%d, %d) = %v, want %v", tt.dFile, tt.dRank, got, tt.want)
+		}
+	}
+}
+
+func TestKnightBend(t *testing.T) {
+	// Two-square leg on the file axis: bend sits at the destination file,
+	// source rank.
+	if bx, by := knightBend(0, 0, 2, 1); bx != 2 || by != 0 {
+		t.Errorf("knightBend(0,0,2,1) = (%d,%d), want (2,0)", bx, by)
+	}
+	// Two-square leg on the rank axis: bend sits at the source file,
+	// destination rank.
+	if bx, by := knightBend(0, 0, 1, 2); bx != 0 || by != 2 {
+		t.Errorf("knightBend(0,0,1,2) = (%d,%d), want (0,2)", bx, by)
+	}
+}
@@ -0,0 +1,70 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/notnil/chess"
+)
+
+// CachedEncoder wraps SVG/PNG rendering with an LRU cache keyed by board
+// FEN and output format. Puzzle servers and PGN viewers frequently
+// re-render the same handful of opening positions across many requests;
+// CachedEncoder avoids paying for a full re-encode on every one.
+//
+// All rendering options are fixed at construction via NewCachedEncoder, so
+// a given CachedEncoder always renders a given board the same way; there
+// is no per-call options parameter to vary the output, and so nothing for
+// the cache key to distinguish beyond the board and format.
+type CachedEncoder struct {
+	opts  []Option
+	cache *lru.Cache
+}
+
+// NewCachedEncoder returns a CachedEncoder that caches up to size rendered
+// outputs, each produced as if by SVG or PNG called with opts.
+func NewCachedEncoder(size int, opts ...Option) (*CachedEncoder, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache: %w", err)
+	}
+	return &CachedEncoder{opts: opts, cache: cache}, nil
+}
+
+// SVG writes b's SVG representation into w, serving it from cache if b has
+// already been rendered.
+func (c *CachedEncoder) SVG(w io.Writer, b *chess.Board) error {
+	return c.render(w, b, "svg", SVG)
+}
+
+// PNG writes b's PNG representation into w, serving it from cache if b has
+// already been rendered.
+func (c *CachedEncoder) PNG(w io.Writer, b *chess.Board) error {
+	return c.render(w, b, "png", PNG)
+}
+
+func (c *CachedEncoder) render(w io.Writer, b *chess.Board, format string, render func(io.Writer, *chess.Board, ...Option) error) error {
+	key := c.cacheKey(format, b)
+	if v, ok := c.cache.Get(key); ok {
+		_, err := w.Write(v.([]byte))
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, b, c.opts...); err != nil {
+		return err
+	}
+
+	c.cache.Add(key, buf.Bytes())
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// cacheKey derives a key from the board's piece placement and the output
+// format. Every other input to rendering is fixed for this CachedEncoder's
+// lifetime, so it doesn't need to be part of the key.
+func (c *CachedEncoder) cacheKey(format string, b *chess.Board) string {
+	return format + "|" + b.String()
+}
@@ -0,0 +1,86 @@
+package image
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/notnil/chess"
+)
+
+func TestCacheKey(t *testing.T) {
+	b := chess.NewGame().Position().Board()
+	c := &CachedEncoder{}
+
+	want := "svg|" + b.String()
+	if got := c.cacheKey("svg", b); got != want {
+		t.Errorf("cacheKey(svg, b) = %q, want %q", got, want)
+	}
+	if got := c.cacheKey("png", b); got == want {
+		t.Errorf("cacheKey(png, b) = %q, want distinct key from svg", got)
+	}
+}
+
+func TestCachedEncoderRenderHitsCacheOnSecondCall(t *testing.T) {
+	cache, err := lru.New(8)
+	if err != nil {
+		t.Fatalf("lru.New: %v", err)
+	}
+	c := &CachedEncoder{cache: cache}
+
+	calls := 0
+	render := func(w io.Writer, _ *chess.Board, _ ...Option) error {
+		calls++
+		_, err := w.Write([]byte("rendered"))
+		return err
+	}
+
+	b := chess.NewGame().Position().Board()
+
+	var buf bytes.Buffer
+	if err := c.render(&buf, b, "svg", render); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls after first render = %d, want 1", calls)
+	}
+
+	buf.Reset()
+	if err := c.render(&buf, b, "svg", render); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls after second render = %d, want 1 (cache hit)", calls)
+	}
+	if got, want := buf.String(), "rendered"; got != want {
+		t.Errorf("buf = %q, want %q", got, want)
+	}
+}
+
+func TestCachedEncoderRenderMissesOnDifferentFormat(t *testing.T) {
+	cache, err := lru.New(8)
+	if err != nil {
+		t.Fatalf("lru.New: %v", err)
+	}
+	c := &CachedEncoder{cache: cache}
+
+	calls := 0
+	render := func(w io.Writer, _ *chess.Board, _ ...Option) error {
+		calls++
+		return nil
+	}
+
+	b := chess.NewGame().Position().Board()
+
+	var buf bytes.Buffer
+	if err := c.render(&buf, b, "svg", render); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if err := c.render(&buf, b, "png", render); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls after rendering two formats = %d, want 2", calls)
+	}
+}
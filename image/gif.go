@@ -0,0 +1,102 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+// defaultAnimateMarkColor highlights the from/to squares of the move that
+// produced each frame of an Animate sequence.
+var defaultAnimateMarkColor = color.RGBA{255, 255, 0, 255}
+
+// Animate writes an animated GIF of game's move sequence into the writer,
+// one frame per position, with each frame after the first marking the
+// squares of the move that produced it. An error is returned if there is
+// an error rendering or writing data. Animate also takes options which can
+// customize the image output; use FrameDelay to control how long each
+// frame is displayed.
+func Animate(w io.Writer, game *chess.Game, opts ...Option) error {
+	e := newEncoder(opts)
+	boardWidth, boardHeight := e.boardSize()
+	f := &gifFormat{
+		rasterCanvas: rasterCanvas{
+			sqWidth:      e.cfg.sqWidth,
+			sqHeight:     e.cfg.sqHeight,
+			width:        boardWidth,
+			height:       boardHeight,
+			theme:        e.cfg.theme,
+			coordStyle:   e.cfg.coordStyle,
+			marginLeft:   e.coordMarginLeft(),
+			marginBottom: e.coordMarginBottom(),
+		},
+		w:     w,
+		delay: e.cfg.frameDelay,
+	}
+
+	if err := f.Init(); err != nil {
+		return fmt.Errorf("failed to init output formatter: %w", err)
+	}
+
+	positions := game.Positions()
+	moves := game.Moves()
+	for i, pos := range positions {
+		frameOpts := append([]Option{}, opts...)
+		if i > 0 {
+			mv := moves[i-1]
+			frameOpts = append(frameOpts, markMoveOption(mv.S1(), mv.S2(), defaultAnimateMarkColor))
+		}
+		frame := newEncoder(frameOpts)
+		if err := frame.drawPosition(pos.Board(), f); err != nil {
+			return err
+		}
+		if err := f.NextFrame(); err != nil {
+			return err
+		}
+	}
+
+	return f.Finalize()
+}
+
+// markMoveOption marks from and to with col, highlighting the move that
+// produced a given animation frame.
+func markMoveOption(from, to chess.Square, col color.Color) Option {
+	return func(_ *config, e *encoder) {
+		e.marks[from] = col
+		e.marks[to] = col
+	}
+}
+
+// gifFormat is a Format that accumulates rendered positions as paletted
+// frames and encodes them as an animated GIF once Finalize is called.
+type gifFormat struct {
+	rasterCanvas
+	w      io.Writer
+	delay  time.Duration
+	frames []*image.Paletted
+	delays []int
+}
+
+// NextFrame quantizes the current drawing to a palette, appends it to the
+// accumulated animation, and resets the canvas for the next position.
+func (g *gifFormat) NextFrame() error {
+	paletted := image.NewPaletted(g.img.Bounds(), palette.WebSafe)
+	draw.Draw(paletted, paletted.Bounds(), g.img, image.Point{}, draw.Src)
+	g.frames = append(g.frames, paletted)
+	g.delays = append(g.delays, int(g.delay/(10*time.Millisecond)))
+	return g.Init()
+}
+
+func (g *gifFormat) Finalize() error {
+	return gif.EncodeAll(g.w, &gif.GIF{
+		Image: g.frames,
+		Delay: g.delays,
+	})
+}
@@ -0,0 +1,60 @@
+package image
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/notnil/chess"
+)
+
+func TestMarkMoveOption(t *testing.T) {
+	e := newEncoder([]Option{markMoveOption(chess.E2, chess.E4, defaultAnimateMarkColor)})
+
+	if got := e.marks[chess.E2]; got != defaultAnimateMarkColor {
+		t.Errorf("marks[E2] = %v, want %v", got, defaultAnimateMarkColor)
+	}
+	if got := e.marks[chess.E4]; got != defaultAnimateMarkColor {
+		t.Errorf("marks[E4] = %v, want %v", got, defaultAnimateMarkColor)
+	}
+	if got := len(e.marks); got != 2 {
+		t.Errorf("len(marks) = %d, want 2", got)
+	}
+}
+
+func TestFrameDelayOption(t *testing.T) {
+	e := newEncoder([]Option{FrameDelay(250 * time.Millisecond)})
+	if got, want := e.cfg.frameDelay, 250*time.Millisecond; got != want {
+		t.Errorf("cfg.frameDelay = %v, want %v", got, want)
+	}
+}
+
+func TestFrameDelayDefault(t *testing.T) {
+	e := newEncoder(nil)
+	if got, want := e.cfg.frameDelay, 500*time.Millisecond; got != want {
+		t.Errorf("default cfg.frameDelay = %v, want %v", got, want)
+	}
+}
+
+func TestAnimateProducesAValidAnimatedGIF(t *testing.T) {
+	g := chess.NewGame()
+	for _, mv := range []string{"e4", "e5", "Nf3"} {
+		if err := g.MoveStr(mv); err != nil {
+			t.Fatalf("MoveStr(%s): %v", mv, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Animate(&buf, g, Resolution(8)); err != nil {
+		t.Fatalf("Animate: %v", err)
+	}
+
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if got, want := len(decoded.Image), len(g.Positions()); got != want {
+		t.Errorf("frame count = %d, want %d", got, want)
+	}
+}
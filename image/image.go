@@ -5,36 +5,132 @@ import (
 	"fmt"
 	"image/color"
 	"io"
-	"strings"
+	"time"
 
 	svg "github.com/ajstarks/svgo"
 	"github.com/notnil/chess"
-	"github.com/notnil/chess/image/internal"
 )
 
+// TextAnchor controls how a coordinate label is positioned within its
+// square, mirroring the SVG text-anchor property. With CoordinateOutside,
+// both anchors instead center the label in the margin beside the grid.
+type TextAnchor int
+
+const (
+	// AnchorStart left-aligns text near the top of the square. Used for
+	// rank labels along the A file.
+	AnchorStart TextAnchor = iota
+	// AnchorEnd right-aligns text near the bottom of the square. Used for
+	// file labels along rank 1.
+	AnchorEnd
+)
+
+// Format is the drawing surface an encoder renders a board onto. Square
+// indices passed to its methods are 0-7 board-grid coordinates, not pixels;
+// implementations are responsible for scaling to their own square size.
 type Format interface {
+	// Init prepares the output canvas for drawing.
 	Init() error
+	// DrawSquare fills the square at (xIdx, yIdx) with col.
 	DrawSquare(xIdx, yIdx int, col color.Color) error
+	// DrawMark highlights the square at (xIdx, yIdx) with col, layered on
+	// top of the square's base color.
+	DrawMark(xIdx, yIdx int, col color.Color) error
+	// DrawPiece renders piece at (xIdx, yIdx).
 	DrawPiece(xIdx, yIdx int, piece chess.Piece) error
+	// DrawText renders s, a rank or file coordinate label, at (xIdx, yIdx)
+	// anchored per anchor.
+	DrawText(xIdx, yIdx int, s string, anchor TextAnchor, col color.Color) error
+	// DrawArrow draws an arrow from the center of the square at (fromX,
+	// fromY) to the center of the square at (toX, toY), annotating a move
+	// or tactical motif.
+	DrawArrow(fromX, fromY, toX, toY int, col color.Color) error
+	// DrawCircle draws a circle outline centered on the square at
+	// (xIdx, yIdx).
+	DrawCircle(xIdx, yIdx int, col color.Color) error
+	// NextFrame stages the current drawing as one frame of an animation and
+	// prepares the canvas for the next one. Formats that cannot represent
+	// more than one frame, such as SVG, return an error.
+	NextFrame() error
+	// Finalize flushes any buffered drawing to the underlying writer.
+	Finalize() error
 }
 
 type svgFormat struct {
 	canvas            *svg.SVG
 	sqWidth, sqHeight int
 	width, height     int
+	theme             PieceTheme
+	coordStyle        CoordinateStyle
+	marginLeft        int
+	marginBottom      int
 }
 
 func (s *svgFormat) Init() error {
 	s.canvas.Start(s.width, s.height)
 	s.canvas.Rect(0, 0, s.width, s.height)
+	return nil
 }
 
 func (s *svgFormat) DrawSquare(xIdx, yIdx int, col color.Color) error {
-	canvas.Rect(x, y, sqWidth, sqHeight, "fill: "+colorToHex(c))
+	x, y := xIdx*s.sqWidth+s.marginLeft, yIdx*s.sqHeight
+	s.canvas.Rect(x, y, s.sqWidth, s.sqHeight, "fill: "+colorToHex(col))
+	return nil
+}
+
+func (s *svgFormat) DrawMark(xIdx, yIdx int, col color.Color) error {
+	x, y := xIdx*s.sqWidth+s.marginLeft, yIdx*s.sqHeight
+	s.canvas.Rect(x, y, s.sqWidth, s.sqHeight, "fill-opacity:0.2;fill: "+colorToHex(col))
+	return nil
 }
 
 func (s *svgFormat) DrawPiece(xIdx, yIdx int, piece chess.Piece) error {
+	x, y := xIdx*s.sqWidth+s.marginLeft, yIdx*s.sqHeight
+	xml := themedPieceXML(s.theme, piece, x, y, s.sqWidth)
+	_, err := io.WriteString(s.canvas.Writer, xml)
+	return err
+}
+
+func (s *svgFormat) DrawText(xIdx, yIdx int, str string, anchor TextAnchor, col color.Color) error {
+	if s.coordStyle == CoordinateNone {
+		return nil
+	}
+
+	style := "font-size:11px;fill: " + colorToHex(col)
+
+	if s.coordStyle == CoordinateOutside {
+		switch anchor {
+		case AnchorStart:
+			// rank label: centered in the left margin, level with its row
+			y := yIdx*s.sqHeight + s.sqHeight/2 + 4
+			s.canvas.Text(s.marginLeft/2, y, str, style+";text-anchor:middle")
+		case AnchorEnd:
+			// file label: centered in the bottom margin, level with its column
+			x := xIdx*s.sqWidth + s.marginLeft + s.sqWidth/2
+			y := s.sqHeight*8 + s.marginBottom/2 + 4
+			s.canvas.Text(x, y, str, style+";text-anchor:middle")
+		}
+		return nil
+	}
+
+	// CoordinateInside (default): label painted inside the square itself.
+	x, y := xIdx*s.sqWidth+s.marginLeft, yIdx*s.sqHeight
+	switch anchor {
+	case AnchorStart:
+		s.canvas.Text(x+(s.sqWidth*1/20), y+(s.sqHeight*5/20), str, style)
+	case AnchorEnd:
+		s.canvas.Text(x+(s.sqWidth*19/20), y+s.sqHeight-(s.sqHeight*1/15), str, "text-anchor:end;"+style)
+	}
+	return nil
+}
 
+func (s *svgFormat) NextFrame() error {
+	return fmt.Errorf("svg: animation is not supported, use Animate for move sequences")
+}
+
+func (s *svgFormat) Finalize() error {
+	s.canvas.End()
+	return nil
 }
 
 type Option func(*config, *encoder)
@@ -43,14 +139,18 @@ type Option func(*config, *encoder)
 // An error is returned if there is there is an error writing data.
 // SVG also takes options which can customize the image output.
 func SVG(w io.Writer, b *chess.Board, opts ...Option) error {
-	e := new(opts)
+	e := newEncoder(opts)
 	boardWidth, boardHeight := e.boardSize()
 	return e.Encode(b, &svgFormat{
-		canvas:   svg.New(w),
-		sqWidth:  e.cfg.sqWidth,
-		sqHeight: e.cfg.sqHeight,
-		width:    boardWidth,
-		height:   boardHeight,
+		canvas:       svg.New(w),
+		sqWidth:      e.cfg.sqWidth,
+		sqHeight:     e.cfg.sqHeight,
+		width:        boardWidth,
+		height:       boardHeight,
+		theme:        e.cfg.theme,
+		coordStyle:   e.cfg.coordStyle,
+		marginLeft:   e.coordMarginLeft(),
+		marginBottom: e.coordMarginBottom(),
 	})
 }
 
@@ -72,6 +172,17 @@ func SquareColors(light, dark color.Color) Option {
 	}
 }
 
+// Resolution sets the number of pixels rendered per board square. It is
+// primarily useful for raster output formats such as PNG, which rasterize
+// at whatever size sqWidth/sqHeight specify; SVG output scales losslessly
+// regardless, though it still honors the dimension.
+func Resolution(pixelsPerSquare int) Option {
+	return func(cfg *config, _ *encoder) {
+		cfg.sqWidth = pixelsPerSquare
+		cfg.sqHeight = pixelsPerSquare
+	}
+}
+
 // MarkSquares is designed to be used as an optional argument
 // to the SVG function.  It marks the given squares with the
 // color.  A possible usage includes marking squares of the
@@ -84,43 +195,74 @@ func MarkSquares(c color.Color, sqs ...chess.Square) Option {
 	}
 }
 
+// FrameDelay sets how long each frame is displayed in animated output
+// produced by Animate. It has no effect on SVG or PNG output. The GIF
+// format quantizes delays to hundredths of a second.
+func FrameDelay(d time.Duration) Option {
+	return func(cfg *config, _ *encoder) {
+		cfg.frameDelay = d
+	}
+}
+
 // config encompasses static parameters about how the board should be rendered.
 type config struct {
-	sqWidth  int
-	sqHeight int
-	flip     bool
-	light    color.Color
-	dark     color.Color
+	sqWidth    int
+	sqHeight   int
+	flip       bool
+	light      color.Color
+	dark       color.Color
+	frameDelay time.Duration
+	theme      PieceTheme
+	coordStyle CoordinateStyle
 }
 
 // encoder encodes chess boards into images.
 type encoder struct {
-	marks map[chess.Square]color.Color
-	cfg   *config
+	marks   map[chess.Square]color.Color
+	circles map[chess.Square]color.Color
+	arrows  []arrow
+	cfg     *config
 }
 
 func (e *encoder) boardSize() (int, int) {
-	return e.cfg.sqWidth * 8, e.cfg.sqHeight * 8
+	return e.cfg.sqWidth*8 + e.coordMarginLeft(), e.cfg.sqHeight*8 + e.coordMarginBottom()
 }
 
-// New returns an encoder that writes to the given writer.
-// New also takes options which can customize the image
-// output.
-func new(options []Option) *encoder {
-	cfg := &config{
-		sqWidth:  45,
-		sqHeight: 45,
-		flip:     false,
-		light:    color.RGBA{235, 209, 166, 1},
-		dark:     color.RGBA{165, 117, 81, 1},
+// coordMarginLeft returns the extra left-hand pixels needed to paint rank
+// labels outside the 8x8 grid, or 0 unless CoordinateOutside is set.
+func (e *encoder) coordMarginLeft() int {
+	if e.cfg.coordStyle == CoordinateOutside {
+		return e.cfg.sqWidth / 2
 	}
+	return 0
+}
 
-	for _, op := range options {
-		op(cfg, &encoder{})
+// coordMarginBottom returns the extra bottom pixels needed to paint file
+// labels outside the 8x8 grid, or 0 unless CoordinateOutside is set.
+func (e *encoder) coordMarginBottom() int {
+	if e.cfg.coordStyle == CoordinateOutside {
+		return e.cfg.sqHeight / 2
+	}
+	return 0
+}
+
+// newEncoder returns an encoder configured by the given options.
+func newEncoder(options []Option) *encoder {
+	cfg := &config{
+		sqWidth:    45,
+		sqHeight:   45,
+		flip:       false,
+		light:      color.RGBA{235, 209, 166, 255},
+		dark:       color.RGBA{165, 117, 81, 255},
+		frameDelay: 500 * time.Millisecond,
+		theme:      cburnettTheme{},
+		coordStyle: CoordinateInside,
 	}
 
 	e := &encoder{
-		marks: map[chess.Square]color.Color{},
+		marks:   map[chess.Square]color.Color{},
+		circles: map[chess.Square]color.Color{},
+		cfg:     cfg,
 	}
 
 	for _, op := range options {
@@ -134,66 +276,96 @@ var (
 	orderOfFiles = []chess.File{chess.FileA, chess.FileB, chess.FileC, chess.FileD, chess.FileE, chess.FileF, chess.FileG, chess.FileH}
 )
 
-// EncodeSVG writes the board SVG representation into
-// the Encoder's writer.  An error is returned if there
-// is there is an error writing data.
+// Encode renders b onto f, a Format, driving every drawing operation
+// (squares, marks, pieces, and coordinate labels) through the Format
+// interface so callers can swap in any backend that implements it.
 func (e *encoder) Encode(b *chess.Board, f Format) error {
-	boardWidth, boardHeight := e.boardSize()
-
-	boardMap := b.SquareMap()
-
 	if err := f.Init(); err != nil {
-		fmt.Errorf("failed to init output formatter: %w", err)
+		return fmt.Errorf("failed to init output formatter: %w", err)
+	}
+
+	if err := e.drawPosition(b, f); err != nil {
+		return err
 	}
 
+	return f.Finalize()
+}
+
+// drawPosition draws b's squares, marks, pieces, and coordinate labels onto
+// f. Unlike Encode, it does not call f.Init or f.Finalize, so callers
+// rendering a sequence of positions onto one Format (such as Animate) can
+// drive those themselves.
+func (e *encoder) drawPosition(b *chess.Board, f Format) error {
+	boardMap := b.SquareMap()
+
 	for i := 0; i < 64; i++ {
 		sq := chess.Square(i)
 		x, y := xyForSquare(sq)
-		// draw square
+
 		c := e.colorForSquare(sq)
-		f.DrawSquare(x, y, c)
-		markColor, ok := e.marks[sq]
-		if ok {
-			canvas.Rect(x, y, sqWidth, sqHeight, "fill-opacity:0.2;fill: "+colorToHex(markColor))
+		if err := f.DrawSquare(x, y, c); err != nil {
+			return fmt.Errorf("failed to draw square: %w", err)
 		}
-		// draw piece
-		p := boardMap[sq]
-		if p != chess.NoPiece {
-			xml := pieceXML(x, y, p)
-			if _, err := io.WriteString(canvas.Writer, xml); err != nil {
-				return err
+
+		if markColor, ok := e.marks[sq]; ok {
+			if err := f.DrawMark(x, y, markColor); err != nil {
+				return fmt.Errorf("failed to draw mark: %w", err)
 			}
 		}
-		// draw rank text on file A
+
+		if p := boardMap[sq]; p != chess.NoPiece {
+			if err := f.DrawPiece(x, y, p); err != nil {
+				return fmt.Errorf("failed to draw piece: %w", err)
+			}
+		}
+
 		txtColor := e.colorForText(sq)
+		// draw rank text on file A
 		if sq.File() == chess.FileA {
-			style := "font-size:11px;fill: " + colorToHex(txtColor)
-			canvas.Text(x+(sqWidth*1/20), y+(sqHeight*5/20), sq.Rank().String(), style)
+			if err := f.DrawText(x, y, sq.Rank().String(), AnchorStart, txtColor); err != nil {
+				return fmt.Errorf("failed to draw rank text: %w", err)
+			}
 		}
 		// draw file text on rank 1
 		if sq.Rank() == chess.Rank1 {
-			style := "text-anchor:end;font-size:11px;fill: " + colorToHex(txtColor)
-			canvas.Text(x+(sqWidth*19/20), y+sqHeight-(sqHeight*1/15), sq.File().String(), style)
+			if err := f.DrawText(x, y, sq.File().String(), AnchorEnd, txtColor); err != nil {
+				return fmt.Errorf("failed to draw file text: %w", err)
+			}
+		}
+	}
+
+	for sq, col := range e.circles {
+		x, y := xyForSquare(sq)
+		if err := f.DrawCircle(x, y, col); err != nil {
+			return fmt.Errorf("failed to draw circle: %w", err)
+		}
+	}
+
+	for _, a := range e.arrows {
+		fx, fy := xyForSquare(a.from)
+		tx, ty := xyForSquare(a.to)
+		if err := f.DrawArrow(fx, fy, tx, ty, a.col); err != nil {
+			return fmt.Errorf("failed to draw arrow: %w", err)
 		}
 	}
-	canvas.End()
+
 	return nil
 }
 
 func (e *encoder) colorForSquare(sq chess.Square) color.Color {
 	sqSum := int(sq.File()) + int(sq.Rank())
 	if sqSum%2 == 0 {
-		return e.dark
+		return e.cfg.dark
 	}
-	return e.light
+	return e.cfg.light
 }
 
 func (e *encoder) colorForText(sq chess.Square) color.Color {
 	sqSum := int(sq.File()) + int(sq.Rank())
 	if sqSum%2 == 0 {
-		return e.light
+		return e.cfg.light
 	}
-	return e.dark
+	return e.cfg.dark
 }
 
 func xyForSquare(sq chess.Square) (x, y int) {
@@ -207,14 +379,6 @@ func colorToHex(c color.Color) string {
 	return fmt.Sprintf("#%02x%02x%02x", uint8(float64(r)+0.5), uint8(float64(g)*1.0+0.5), uint8(float64(b)*1.0+0.5))
 }
 
-func pieceXML(x, y int, p chess.Piece) string {
-	fileName := fmt.Sprintf("pieces/%s%s.svg", p.Color().String(), pieceTypeMap[p.Type()])
-	svgStr := string(internal.MustAsset(fileName))
-	old := `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="45" height="45">`
-	new := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="360" height="360" viewBox="%d %d 360 360">`, (-1 * x), (-1 * y))
-	return strings.Replace(svgStr, old, new, 1)
-}
-
 var (
 	pieceTypeMap = map[chess.PieceType]string{
 		chess.King:   "K",
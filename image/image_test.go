@@ -0,0 +1,39 @@
+package image
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/notnil/chess"
+)
+
+func TestResolutionOption(t *testing.T) {
+	e := newEncoder([]Option{Resolution(90)})
+	if e.cfg.sqWidth != 90 || e.cfg.sqHeight != 90 {
+		t.Errorf("Resolution(90) = %dx%d, want 90x90", e.cfg.sqWidth, e.cfg.sqHeight)
+	}
+}
+
+func TestResolutionDefault(t *testing.T) {
+	e := newEncoder(nil)
+	if e.cfg.sqWidth != 45 || e.cfg.sqHeight != 45 {
+		t.Errorf("default resolution = %dx%d, want 45x45", e.cfg.sqWidth, e.cfg.sqHeight)
+	}
+}
+
+// pngSignature is the fixed 8-byte header every PNG stream starts with.
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+func TestPNGProducesAValidPNGStream(t *testing.T) {
+	b := chess.NewGame().Position().Board()
+
+	var buf bytes.Buffer
+	if err := PNG(&buf, b, Resolution(8)); err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	if got := buf.Bytes(); len(got) < len(pngSignature) {
+		t.Fatalf("PNG output too short to contain the PNG signature: %d bytes", len(got))
+	} else if !bytes.Equal(got[:len(pngSignature)], pngSignature) {
+		t.Fatalf("PNG output doesn't start with the PNG signature, got % x", got[:len(pngSignature)])
+	}
+}
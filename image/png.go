@@ -0,0 +1,192 @@
+package image
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"regexp"
+	"sync"
+
+	"github.com/notnil/chess"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// markAlpha is the alpha value used when layering a DrawMark highlight over
+// a square's base color, matching the SVG backend's fill-opacity:0.2.
+const markAlpha = 0x33
+
+// PNG writes a raster PNG representation of the board into the writer.
+// An error is returned if there is there is an error rendering or writing
+// data. PNG also takes options which can customize the image output; use
+// Resolution to control the number of pixels rendered per square.
+func PNG(w io.Writer, b *chess.Board, opts ...Option) error {
+	e := newEncoder(opts)
+	boardWidth, boardHeight := e.boardSize()
+	return e.Encode(b, &pngFormat{
+		w: w,
+		rasterCanvas: rasterCanvas{
+			sqWidth:      e.cfg.sqWidth,
+			sqHeight:     e.cfg.sqHeight,
+			width:        boardWidth,
+			height:       boardHeight,
+			theme:        e.cfg.theme,
+			coordStyle:   e.cfg.coordStyle,
+			marginLeft:   e.coordMarginLeft(),
+			marginBottom: e.coordMarginBottom(),
+		},
+	})
+}
+
+// rasterCanvas implements the square/mark/piece/text drawing shared by every
+// raster Format (pngFormat, gifFormat) onto an in-memory image.RGBA.
+type rasterCanvas struct {
+	img               *image.RGBA
+	sqWidth, sqHeight int
+	width, height     int
+	theme             PieceTheme
+	coordStyle        CoordinateStyle
+	marginLeft        int
+	marginBottom      int
+}
+
+func (c *rasterCanvas) Init() error {
+	c.img = image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	return nil
+}
+
+func (c *rasterCanvas) DrawSquare(xIdx, yIdx int, col color.Color) error {
+	draw.Draw(c.img, c.squareRect(xIdx, yIdx), image.NewUniform(col), image.Point{}, draw.Src)
+	return nil
+}
+
+func (c *rasterCanvas) DrawMark(xIdx, yIdx int, col color.Color) error {
+	mask := image.NewUniform(color.Alpha{A: markAlpha})
+	draw.DrawMask(c.img, c.squareRect(xIdx, yIdx), image.NewUniform(col), image.Point{}, mask, image.Point{}, draw.Over)
+	return nil
+}
+
+func (c *rasterCanvas) DrawPiece(xIdx, yIdx int, piece chess.Piece) error {
+	pieceImg, err := rasterizedPiece(c.theme, piece, c.sqWidth)
+	if err != nil {
+		return err
+	}
+	x, y := xIdx*c.sqWidth+c.marginLeft, yIdx*c.sqHeight
+	pt := image.Pt(x, y)
+	draw.Draw(c.img, pieceImg.Bounds().Add(pt), pieceImg, image.Point{}, draw.Over)
+	return nil
+}
+
+func (c *rasterCanvas) DrawText(xIdx, yIdx int, str string, anchor TextAnchor, col color.Color) error {
+	if c.coordStyle == CoordinateNone {
+		return nil
+	}
+
+	d := &font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: basicfont.Face7x13,
+	}
+
+	if c.coordStyle == CoordinateOutside {
+		switch anchor {
+		case AnchorStart:
+			y := yIdx*c.sqHeight + c.sqHeight/2 + 4
+			d.Dot = fixed.P(c.marginLeft/2-d.MeasureString(str).Ceil()/2, y)
+		case AnchorEnd:
+			x := xIdx*c.sqWidth + c.marginLeft + c.sqWidth/2
+			y := c.sqHeight*8 + c.marginBottom/2 + 4
+			d.Dot = fixed.P(x-d.MeasureString(str).Ceil()/2, y)
+		}
+		d.DrawString(str)
+		return nil
+	}
+
+	// CoordinateInside (default): label painted inside the square itself.
+	x, y := xIdx*c.sqWidth+c.marginLeft, yIdx*c.sqHeight
+	switch anchor {
+	case AnchorStart:
+		d.Dot = fixed.P(x+c.sqWidth*1/20, y+c.sqHeight*5/20)
+	case AnchorEnd:
+		d.Dot = fixed.P(x+c.sqWidth-c.sqWidth*1/20-d.MeasureString(str).Ceil(), y+c.sqHeight-c.sqHeight*1/15)
+	}
+	d.DrawString(str)
+	return nil
+}
+
+func (c *rasterCanvas) squareRect(xIdx, yIdx int) image.Rectangle {
+	x, y := xIdx*c.sqWidth+c.marginLeft, yIdx*c.sqHeight
+	return image.Rect(x, y, x+c.sqWidth, y+c.sqHeight)
+}
+
+// pngFormat is a Format that rasterizes a board onto a rasterCanvas and
+// encodes the result as a single PNG.
+type pngFormat struct {
+	rasterCanvas
+	w io.Writer
+}
+
+func (p *pngFormat) NextFrame() error {
+	return fmt.Errorf("png: does not support multiple frames, use Animate for move sequences")
+}
+
+func (p *pngFormat) Finalize() error {
+	return png.Encode(p.w, p.img)
+}
+
+var (
+	pieceCacheMu sync.Mutex
+	pieceCache   = map[string]*image.RGBA{}
+)
+
+// rasterizedPiece returns p's artwork, as supplied by theme, rasterized to
+// a size x size RGBA image. It renders on first use and caches the result
+// keyed on the actual SVG content theme returns (not the theme's Go type,
+// which says nothing about which piece set a particular instance holds)
+// together with the size, so distinct theme instances of the same type
+// never collide.
+func rasterizedPiece(theme PieceTheme, p chess.Piece, size int) (*image.RGBA, error) {
+	svgBytes := normalizeSVGColors(theme.SVG(p))
+	sum := sha256.Sum256(svgBytes)
+	key := fmt.Sprintf("%x@%d", sum, size)
+
+	pieceCacheMu.Lock()
+	defer pieceCacheMu.Unlock()
+	if img, ok := pieceCache[key]; ok {
+		return img, nil
+	}
+
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(svgBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse piece svg for %s %s: %w", p.Color(), pieceTypeMap[p.Type()], err)
+	}
+	icon.SetTarget(0, 0, float64(size), float64(size))
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	raster := rasterx.NewDasher(size, size, scanner)
+	icon.Draw(raster, 1.0)
+
+	pieceCache[key] = img
+	return img, nil
+}
+
+// bareHexColor matches a fill/stroke color given as a bare 3- or 6-digit hex
+// triplet, e.g. "fill:000000", with no leading '#'.
+var bareHexColor = regexp.MustCompile(`(fill|stroke):\s*([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})\b`)
+
+// normalizeSVGColors adds a missing '#' to bare hex fill/stroke colors in
+// svg. Some of the bundled Cburnett piece artwork writes colors this way
+// (e.g. "fill:000000" on the black queen and rook), which oksvg's color
+// parser rejects outright with "param mismatch".
+func normalizeSVGColors(svg []byte) []byte {
+	return bareHexColor.ReplaceAll(svg, []byte("$1:#$2"))
+}
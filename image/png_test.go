@@ -0,0 +1,74 @@
+package image
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/notnil/chess"
+)
+
+func TestNormalizeSVGColorsAddsMissingHash(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "bare 6-digit fill",
+			in:   `<path style="fill:000000;stroke:none"/>`,
+			want: `<path style="fill:#000000;stroke:none"/>`,
+		},
+		{
+			name: "bare 3-digit stroke",
+			in:   `<path style="stroke:fff"/>`,
+			want: `<path style="stroke:#fff"/>`,
+		},
+		{
+			name: "already hashed color is left alone",
+			in:   `<path style="fill:#000000"/>`,
+			want: `<path style="fill:#000000"/>`,
+		},
+		{
+			name: "non-hex value is left alone",
+			in:   `<rect fill="red"/>`,
+			want: `<rect fill="red"/>`,
+		},
+	}
+	for _, tt := range tests {
+		if got := string(normalizeSVGColors([]byte(tt.in))); got != tt.want {
+			t.Errorf("%s: normalizeSVGColors(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPNGSquareColorIsFullyOpaque(t *testing.T) {
+	b := chess.NewGame().Position().Board()
+
+	opts := []Option{Resolution(8), WithCoordinateStyle(CoordinateNone)}
+	var buf bytes.Buffer
+	if err := PNG(&buf, b, opts...); err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+
+	// a3 is empty at the starting position, so its corner pixel is just
+	// the square's rendered background color (coordinate labels are
+	// disabled above so they can't paint over it) -- which should come
+	// back fully opaque, not the near-invisible ~0.4% opacity a stray
+	// alpha:1 default would produce.
+	e := newEncoder(opts)
+	x, y := xyForSquare(chess.A3)
+	wantR, wantG, wantB, wantA := e.colorForSquare(chess.A3).RGBA()
+	gotR, gotG, gotB, gotA := img.At(x*e.cfg.sqWidth, y*e.cfg.sqHeight).RGBA()
+	if gotR != wantR || gotG != wantG || gotB != wantB || gotA != wantA {
+		t.Errorf("a3 corner pixel = (%d,%d,%d,%d), want (%d,%d,%d,%d)", gotR, gotG, gotB, gotA, wantR, wantG, wantB, wantA)
+	}
+	if gotA != 0xffff {
+		t.Errorf("a3 alpha = %#x, want fully opaque 0xffff", gotA)
+	}
+}
@@ -0,0 +1,83 @@
+package image
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/notnil/chess"
+	"github.com/notnil/chess/image/internal"
+)
+
+// PieceTheme supplies the SVG markup used to render each piece, letting
+// callers ship Merida, Alpha, or other custom piece sets without forking
+// this package.
+type PieceTheme interface {
+	// SVG returns p's artwork as a single <svg>...</svg> document.
+	SVG(p chess.Piece) []byte
+}
+
+// cburnettTheme is the default PieceTheme, backed by the Wikimedia Cburnett
+// piece set bundled in internal.
+type cburnettTheme struct{}
+
+func (cburnettTheme) SVG(p chess.Piece) []byte {
+	fileName := fmt.Sprintf("pieces/%s%s.svg", p.Color().String(), pieceTypeMap[p.Type()])
+	return internal.MustAsset(fileName)
+}
+
+// WithPieceTheme overrides the default Cburnett piece set with theme.
+func WithPieceTheme(theme PieceTheme) Option {
+	return func(cfg *config, _ *encoder) {
+		cfg.theme = theme
+	}
+}
+
+// CoordinateStyle controls whether and where rank/file coordinate labels
+// are drawn.
+type CoordinateStyle int
+
+const (
+	// CoordinateInside paints labels inside the board's edge squares, the
+	// default.
+	CoordinateInside CoordinateStyle = iota
+	// CoordinateOutside paints labels in a margin added outside the 8x8
+	// grid, rather than overlapping the board itself.
+	CoordinateOutside
+	// CoordinateNone omits rank/file labels entirely.
+	CoordinateNone
+)
+
+// WithCoordinateStyle controls how rank/file coordinate labels are drawn.
+func WithCoordinateStyle(style CoordinateStyle) Option {
+	return func(cfg *config, _ *encoder) {
+		cfg.coordStyle = style
+	}
+}
+
+// svgRootTag matches a piece theme's root <svg ...> element so it can be
+// re-rooted with the position and size needed to place it on the board
+// canvas, regardless of the theme's own width/height/viewBox attributes.
+var svgRootTag = regexp.MustCompile(`<svg[^>]*>`)
+
+// svgViewBox and svgSize read the theme's own coordinate system off its
+// root tag, so the re-rooted element can scale the artwork to fit.
+var (
+	svgViewBox = regexp.MustCompile(`\sviewBox="([^"]*)"`)
+	svgSize    = regexp.MustCompile(`\swidth="([^"]*)"\s+height="([^"]*)"`)
+)
+
+// themedPieceXML returns p's SVG markup from theme, re-rooted as a
+// width x width square positioned at (x, y) in the parent canvas, with the
+// artwork scaled to fill it.
+func themedPieceXML(theme PieceTheme, p chess.Piece, x, y, width int) string {
+	src := string(theme.SVG(p))
+	root := svgRootTag.FindString(src)
+	viewBox := ""
+	if m := svgViewBox.FindStringSubmatch(root); m != nil {
+		viewBox = fmt.Sprintf(` viewBox="%s"`, m[1])
+	} else if m := svgSize.FindStringSubmatch(root); m != nil {
+		viewBox = fmt.Sprintf(` viewBox="0 0 %s %s"`, m[1], m[2])
+	}
+	newRoot := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" x="%d" y="%d" width="%d" height="%d"%s>`, x, y, width, width, viewBox)
+	return svgRootTag.ReplaceAllString(src, newRoot)
+}
@@ -0,0 +1,78 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/notnil/chess"
+)
+
+func TestCoordMarginsOnlyAppliedForOutsideStyle(t *testing.T) {
+	inside := newEncoder([]Option{WithCoordinateStyle(CoordinateInside), Resolution(40)})
+	if got := inside.coordMarginLeft(); got != 0 {
+		t.Errorf("CoordinateInside marginLeft = %d, want 0", got)
+	}
+	if got := inside.coordMarginBottom(); got != 0 {
+		t.Errorf("CoordinateInside marginBottom = %d, want 0", got)
+	}
+
+	none := newEncoder([]Option{WithCoordinateStyle(CoordinateNone), Resolution(40)})
+	if got := none.coordMarginLeft(); got != 0 {
+		t.Errorf("CoordinateNone marginLeft = %d, want 0", got)
+	}
+	if got := none.coordMarginBottom(); got != 0 {
+		t.Errorf("CoordinateNone marginBottom = %d, want 0", got)
+	}
+
+	outside := newEncoder([]Option{WithCoordinateStyle(CoordinateOutside), Resolution(40)})
+	if got, want := outside.coordMarginLeft(), 20; got != want {
+		t.Errorf("CoordinateOutside marginLeft = %d, want %d", got, want)
+	}
+	if got, want := outside.coordMarginBottom(), 20; got != want {
+		t.Errorf("CoordinateOutside marginBottom = %d, want %d", got, want)
+	}
+
+	w, h := outside.boardSize()
+	if got, want := w, 40*8+20; got != want {
+		t.Errorf("boardSize() width = %d, want %d", got, want)
+	}
+	if got, want := h, 40*8+20; got != want {
+		t.Errorf("boardSize() height = %d, want %d", got, want)
+	}
+}
+
+// fakeTheme is a PieceTheme whose content is distinguished only by the SVG
+// bytes it carries, not by its Go type, mirroring how a real caller might
+// construct multiple instances of a directory- or byte-backed theme.
+type fakeTheme struct {
+	svg []byte
+}
+
+func (f fakeTheme) SVG(chess.Piece) []byte { return f.svg }
+
+func TestRasterizedPieceDoesNotCollideAcrossThemeInstances(t *testing.T) {
+	// Styled with a bare (unhashed) hex color, like the bundled Cburnett
+	// black queen/rook artwork, to also exercise normalizeSVGColors.
+	themeA := fakeTheme{svg: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="8" height="8" style="fill:ff0000"/></svg>`)}
+	themeB := fakeTheme{svg: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="8" height="8" style="fill:0000ff"/></svg>`)}
+
+	imgA, err := rasterizedPiece(themeA, chess.WhitePawn, 8)
+	if err != nil {
+		t.Fatalf("rasterizedPiece(themeA): %v", err)
+	}
+	imgB, err := rasterizedPiece(themeB, chess.WhitePawn, 8)
+	if err != nil {
+		t.Fatalf("rasterizedPiece(themeB): %v", err)
+	}
+
+	if imgA == imgB {
+		t.Fatal("rasterizedPiece returned the same cached image for two theme instances with different SVG content")
+	}
+
+	imgA2, err := rasterizedPiece(themeA, chess.WhitePawn, 8)
+	if err != nil {
+		t.Fatalf("rasterizedPiece(themeA) again: %v", err)
+	}
+	if imgA2 != imgA {
+		t.Fatal("rasterizedPiece didn't reuse the cached image for a repeat call with the same theme content")
+	}
+}